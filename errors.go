@@ -0,0 +1,85 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"mime"
+	"net/http"
+)
+
+// HTTPError is returned by Request when the server responds with a
+// status code outside the 2xx range. Unlike a plain error built from
+// the status line, it carries the response Header and Body so that
+// callers can inspect machine-readable error details that APIs put in
+// the body (RFC 7807 application/problem+json, GitHub-style {message,
+// errors[]}, etc.) instead of discarding them. Use errors.As to
+// retrieve it from a wrapped error, and AsProblem to decode an RFC
+// 7807 document from it. Error returns just Status, so existing code
+// that only printed or compared the error message is unaffected.
+type HTTPError struct {
+	StatusCode int
+	Status     string
+	Header     http.Header
+	Body       []byte
+	URL        string
+	Method     string
+}
+
+func (e *HTTPError) Error() string { return e.Status }
+
+// Problem is a decoded RFC 7807 application/problem+json document.
+// Members holds any extension members beyond the five standard
+// fields. See AsProblem.
+type Problem struct {
+	Type     string
+	Title    string
+	Status   int
+	Detail   string
+	Instance string
+	Members  map[string]any
+}
+
+// AsProblem reports whether err is (or wraps) an *HTTPError whose
+// Content-Type header is application/problem+json and whose Body is a
+// valid RFC 7807 document, decoding it into a Problem if so. A
+// response that merely happens to contain valid JSON under a
+// different Content-Type (e.g. the GitHub-style {message, errors[]}
+// format) is reported as false, not mistaken for a problem document.
+func AsProblem(err error) (*Problem, bool) {
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) {
+		return nil, false
+	}
+
+	mediatype, _, mimeErr := mime.ParseMediaType(httpErr.Header.Get(`Content-Type`))
+	if mimeErr != nil || mediatype != `application/problem+json` {
+		return nil, false
+	}
+
+	var raw map[string]any
+	if jsonErr := json.Unmarshal(httpErr.Body, &raw); jsonErr != nil {
+		return nil, false
+	}
+
+	p := &Problem{Members: map[string]any{}}
+	for k, v := range raw {
+		switch k {
+		case `type`:
+			p.Type, _ = v.(string)
+		case `title`:
+			p.Title, _ = v.(string)
+		case `status`:
+			if f, ok := v.(float64); ok {
+				p.Status = int(f)
+			}
+		case `detail`:
+			p.Detail, _ = v.(string)
+		case `instance`:
+			p.Instance, _ = v.(string)
+		default:
+			p.Members[k] = v
+		}
+	}
+
+	return p, true
+}
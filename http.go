@@ -14,7 +14,6 @@ package http
 import (
 	"context"
 	"encoding/json"
-	"fmt"
 	"io"
 	"net/http"
 	"net/url"
@@ -80,66 +79,122 @@ type DELETE struct {
 // values to the HTTP Client and unmarshals the response into the data
 // struct passed by pointer (out, which may already contain populated
 // data fields). Request also observes the package global http.TimeOut
-// Any status code other than 200 returns an error. Also see
-// Get, Post, Put, Patch, and Delete.
-func Request[T any](method, uri string, in url.Values, out *T) error {
-	var err error
-	var req *http.Request
-
-	// encode any input data
-	switch method {
-	case "GET", "DELETE":
-		req, err = http.NewRequest(method, uri, nil)
-		if in != nil {
-			q := req.URL.Query()
-			for k, values := range in {
-				for _, value := range values {
-					q.Add(k, value)
+// Any status code other than 2xx returns an error. When the package
+// global Retry policy is set, transient failures (network errors, 429,
+// and 5xx responses) are retried, rebuilding the form-encoded body for
+// each attempt and honoring a Retry-After response header over the
+// policy's own computed backoff; all attempts share the single
+// deadline set by TimeOut (or the call's WithTimeout Option). Opts may
+// be used to set per-call headers, cookies, auth, query parameters, a
+// parent context, or an alternate Client without mutating the package
+// globals, making Request safe to call concurrently with differing
+// needs. Also see Get, Post, Put, Patch, and Delete.
+func Request[T any](method, uri string, in url.Values, out *T, opts ...Option) error {
+	o := newOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	client := Client
+	if o.Client != nil {
+		client = o.Client
+	}
+
+	timeout := time.Duration(TimeOut) * time.Second
+	if o.Timeout > 0 {
+		timeout = o.Timeout
+	}
+
+	parent := context.Background()
+	if o.Context != nil {
+		parent = o.Context
+	}
+	ctx, cancel := context.WithTimeout(parent, timeout)
+	defer cancel()
+
+	buildReq := func() (*http.Request, error) {
+		var err error
+		var req *http.Request
+
+		// encode any input data
+		switch method {
+		case "GET", "DELETE":
+			req, err = http.NewRequest(method, uri, nil)
+			if err != nil {
+				return nil, err
+			}
+			if in != nil || len(o.QueryParams) > 0 {
+				q := req.URL.Query()
+				for k, values := range in {
+					for _, value := range values {
+						q.Add(k, value)
+					}
+				}
+				for k, values := range o.QueryParams {
+					for _, value := range values {
+						q.Add(k, value)
+					}
 				}
+				req.URL.RawQuery = q.Encode()
 			}
-			req.URL.RawQuery = q.Encode()
-		}
-		break
-	case "POST", "PUT", "PATCH":
-		var inreader *strings.Reader = nil
-		var inlength string
-		if in != nil {
-			encoded := in.Encode()
-			inreader = strings.NewReader(encoded)
-			inlength = strconv.Itoa(len(encoded))
+			break
+		case "POST", "PUT", "PATCH":
+			var inreader *strings.Reader = nil
+			var inlength string
+			if in != nil {
+				encoded := in.Encode()
+				inreader = strings.NewReader(encoded)
+				inlength = strconv.Itoa(len(encoded))
+			}
+
+			req, err = http.NewRequest(method, uri, inreader)
+			if err != nil {
+				return nil, err
+			}
+			if in != nil {
+				req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+				req.Header.Add("Content-Length", inlength)
+			}
+			if len(o.QueryParams) > 0 {
+				q := req.URL.Query()
+				for k, values := range o.QueryParams {
+					for _, value := range values {
+						q.Add(k, value)
+					}
+				}
+				req.URL.RawQuery = q.Encode()
+			}
+			break
 		}
 
-		req, err = http.NewRequest(method, uri, inreader)
-		if err != nil {
-			return err
+		for k, values := range o.Header {
+			ck := http.CanonicalHeaderKey(k)
+			for _, v := range values {
+				// Set, not Add, for Content-Type so a WithHeader override
+				// replaces the form-encoded Content-Type set above instead
+				// of stacking a second value on the wire.
+				if ck == `Content-Type` {
+					req.Header.Set(ck, v)
+					continue
+				}
+				req.Header.Add(k, v)
+			}
 		}
-		if in != nil {
-			req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
-			req.Header.Add("Content-Length", inlength)
+		for _, c := range o.Cookies {
+			req.AddCookie(c)
+		}
+		if o.HasBasicAuth {
+			req.SetBasicAuth(o.BasicUser, o.BasicPass)
 		}
-		break
-	}
-
-	//  upgrade request to with context and TimeOut
-	dur := time.Duration(time.Second * time.Duration(TimeOut))
-	ctx, cancel := context.WithTimeout(context.Background(), dur)
-	defer cancel()
 
-	if req == nil {
-		fmt.Println("Hello World")
+		return req, nil
 	}
 
-	req = req.WithContext(ctx)
-
-	// do the request and check status code
-	res, err := Client.Do(req)
+	res, err := doWithRetry(ctx, client, method, uri, buildReq)
 	if err != nil {
 		return err
 	}
-
-	if !(200 <= res.StatusCode && res.StatusCode < 300) {
-		return fmt.Errorf(res.Status)
-	}
+	defer res.Body.Close()
 
 	// read all the body of the response and unmarshal it
 	buf, err := io.ReadAll(res.Body)
@@ -149,29 +204,42 @@ func Request[T any](method, uri string, in url.Values, out *T) error {
 	return json.Unmarshal(buf, out)
 }
 
-// Get sends a GET Request.
-func Get[T any](url string, in url.Values, out *T) error {
-	return Request(`GET`, url, in, out)
+// Get sends a GET Request. Opts may be used to set per-call headers,
+// cookies, auth, query parameters, a parent context, or an alternate
+// Client without mutating the package globals TimeOut and Client.
+func Get[T any](url string, in url.Values, out *T, opts ...Option) error {
+	return Request(`GET`, url, in, out, opts...)
 }
 
-// Post sends a POST Request.
-func Post[T any](url string, in url.Values, out *T) error {
-	return Request(`POST`, url, in, out)
+// Post sends a POST Request. Opts may be used to set per-call
+// headers, cookies, auth, query parameters, a parent context, or an
+// alternate Client without mutating the package globals TimeOut and
+// Client.
+func Post[T any](url string, in url.Values, out *T, opts ...Option) error {
+	return Request(`POST`, url, in, out, opts...)
 }
 
-// Put sends a POST Request.
-func Put[T any](url string, in url.Values, out *T) error {
-	return Request(`PUT`, url, in, out)
+// Put sends a PUT Request. Opts may be used to set per-call headers,
+// cookies, auth, query parameters, a parent context, or an alternate
+// Client without mutating the package globals TimeOut and Client.
+func Put[T any](url string, in url.Values, out *T, opts ...Option) error {
+	return Request(`PUT`, url, in, out, opts...)
 }
 
-// Patch sends a PATCH Request.
-func Patch[T any](url string, in url.Values, out *T) error {
-	return Request(`PATCH`, url, in, out)
+// Patch sends a PATCH Request. Opts may be used to set per-call
+// headers, cookies, auth, query parameters, a parent context, or an
+// alternate Client without mutating the package globals TimeOut and
+// Client.
+func Patch[T any](url string, in url.Values, out *T, opts ...Option) error {
+	return Request(`PATCH`, url, in, out, opts...)
 }
 
-// Delete sends a DELETE Request.
-func Delete[T any](url string, out *T) error {
-	return Request(`DELETE`, url, nil, out)
+// Delete sends a DELETE Request. Opts may be used to set per-call
+// headers, cookies, auth, query parameters, a parent context, or an
+// alternate Client without mutating the package globals TimeOut and
+// Client.
+func Delete[T any](url string, out *T, opts ...Option) error {
+	return Request(`DELETE`, url, nil, out, opts...)
 }
 
 // ReqRecipe is a "bottled" HTTP request, that can be used with http.Pipe.
@@ -1,16 +1,35 @@
 package http_test
 
 import (
+	"context"
+	stdjson "encoding/json"
 	"fmt"
+	"io"
 	_http "net/http"
 	ht "net/http/httptest"
 	"net/url"
 	"strconv"
+	"strings"
+	"testing"
+	"time"
 
 	"github.com/rwxrob/http"
 	"github.com/rwxrob/json"
 )
 
+// upperJSONEncoder is a test-only http.Encoder that marshals as JSON
+// and then upper-cases the result, used to prove WithEncoder selects
+// a non-default Encoder for a single call.
+type upperJSONEncoder struct{}
+
+func (upperJSONEncoder) Encode(v any) ([]byte, string, error) {
+	buf, err := stdjson.Marshal(v)
+	if err != nil {
+		return nil, ``, err
+	}
+	return []byte(strings.ToUpper(string(buf))), `application/x-upper-json`, nil
+}
+
 func ExampleGet() {
 
 	// setup mock web service
@@ -369,6 +388,434 @@ func ExamplePipe() {
 //	// {"Name":"Rob","Greeting":"hello"}
 //}
 
+func ExamplePostJSON() {
+
+	// setup mock web service that echoes the decoded JSON body back
+	handler := _http.HandlerFunc(
+		func(w _http.ResponseWriter, r *_http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			io.Copy(w, r.Body)
+		})
+	svr := ht.NewServer(handler)
+	defer svr.Close()
+
+	type Greeting struct {
+		Word string `json:"word"`
+		Name string `json:"name"`
+	}
+
+	in := Greeting{Word: "hello", Name: "Roberto"}
+	var out Greeting
+	if err := http.PostJSON(svr.URL, &in, &out); err != nil {
+		fmt.Println(err)
+	}
+
+	json.Object[Greeting]{out}.Print()
+
+	// Output:
+	// {"word":"hello","name":"Roberto"}
+}
+
+func ExampleRequestJSON_textPlain() {
+
+	// setup mock web service that returns a plain text body
+	handler := _http.HandlerFunc(
+		func(w _http.ResponseWriter, r *_http.Request) {
+			w.Header().Set("Content-Type", "text/plain")
+			fmt.Fprint(w, "hello, Roberto")
+		})
+	svr := ht.NewServer(handler)
+	defer svr.Close()
+
+	var out string
+	if err := http.RequestJSON[any, string]("GET", svr.URL, nil, &out); err != nil {
+		fmt.Println(err)
+	}
+
+	fmt.Println(out)
+
+	// Output:
+	// hello, Roberto
+}
+
+func ExamplePostJSON_options() {
+
+	// setup mock web service that echoes back the auth header it
+	// received alongside the raw JSON body it was sent
+	handler := _http.HandlerFunc(
+		func(w _http.ResponseWriter, r *_http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"auth":%q,"body":%s}`, r.Header.Get("Authorization"), body)
+		})
+	svr := ht.NewServer(handler)
+	defer svr.Close()
+
+	type Greeting struct {
+		Name string `json:"name"`
+	}
+	type Data struct {
+		Auth string   `json:"auth"`
+		Body Greeting `json:"body"`
+	}
+
+	in := Greeting{Name: "Roberto"}
+	var out Data
+	err := http.PostJSON(svr.URL, &in, &out, http.WithBearer("s3cr3t"))
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	json.Object[Data]{out}.Print()
+
+	// Output:
+	// {"auth":"Bearer s3cr3t","body":{"name":"Roberto"}}
+}
+
+func ExamplePostJSON_contentTypeOverride() {
+
+	// setup mock web service that reports the single Content-Type
+	// header value it received
+	handler := _http.HandlerFunc(
+		func(w _http.ResponseWriter, r *_http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			out := fmt.Sprintf(`{"contentTypes":%d,"contentType":%q}`,
+				len(r.Header.Values("Content-Type")), r.Header.Get("Content-Type"))
+			fmt.Fprint(w, out)
+		})
+	svr := ht.NewServer(handler)
+	defer svr.Close()
+
+	type Greeting struct {
+		Name string `json:"name"`
+	}
+	type Data struct {
+		ContentTypes int    `json:"contentTypes"`
+		ContentType  string `json:"contentType"`
+	}
+
+	in := Greeting{Name: "Roberto"}
+	var out Data
+	err := http.PostJSON(svr.URL, &in, &out,
+		http.WithHeader("Content-Type", "application/vnd.api+json"))
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	json.Object[Data]{out}.Print()
+
+	// Output:
+	// {"contentTypes":1,"contentType":"application/vnd.api+json"}
+}
+
+func ExamplePostJSON_retry() {
+
+	// setup mock web service that fails twice with 503 before succeeding
+	var tries int
+	handler := _http.HandlerFunc(
+		func(w _http.ResponseWriter, r *_http.Request) {
+			tries++
+			if tries < 3 {
+				w.WriteHeader(503)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"word":"hello","name":"Rob"}`)
+		})
+	svr := ht.NewServer(handler)
+	defer svr.Close()
+
+	http.Retry = &http.RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   time.Millisecond,
+		MaxDelay:    10 * time.Millisecond,
+		Multiplier:  2,
+		OnRetry: func(attempt int, err error, delay time.Duration) {
+			fmt.Println("retrying attempt", attempt)
+		},
+	}
+	defer func() { http.Retry = nil }()
+
+	type Greeting struct {
+		Word string `json:"word"`
+		Name string `json:"name"`
+	}
+
+	in := Greeting{Word: "hi", Name: "Roberto"}
+	var out Greeting
+	if err := http.PostJSON(svr.URL, &in, &out); err != nil {
+		fmt.Println(err)
+	}
+
+	json.Object[Greeting]{out}.Print()
+
+	// Output:
+	// retrying attempt 1
+	// retrying attempt 2
+	// {"word":"hello","name":"Rob"}
+}
+
+func ExamplePostJSON_encoder() {
+
+	// a trivial Encoder that upper-cases the JSON encoding of v,
+	// registered under its own Content-Type so it never shadows the
+	// default application/json Encoder
+	upperJSON := upperJSONEncoder{}
+	http.Encoders["application/x-upper-json"] = upperJSON
+	defer delete(http.Encoders, "application/x-upper-json")
+
+	// setup mock web service that echoes back the request body and its
+	// Content-Type
+	handler := _http.HandlerFunc(
+		func(w _http.ResponseWriter, r *_http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			w.Header().Set("Content-Type", "application/json")
+			out := fmt.Sprintf(`{"contentType":%q,"body":%q}`,
+				r.Header.Get("Content-Type"), string(body))
+			fmt.Fprint(w, out)
+		})
+	svr := ht.NewServer(handler)
+	defer svr.Close()
+
+	type Greeting struct {
+		Name string `json:"name"`
+	}
+	type Data struct {
+		ContentType string `json:"contentType"`
+		Body        string `json:"body"`
+	}
+
+	in := Greeting{Name: "Roberto"}
+	var out Data
+	err := http.PostJSON(svr.URL, &in, &out,
+		http.WithEncoder("application/x-upper-json"))
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	json.Object[Data]{out}.Print()
+
+	// Output:
+	// {"contentType":"application/x-upper-json","body":"{\"NAME\":\"ROBERTO\"}"}
+}
+
+func ExampleGet_retry() {
+
+	// setup mock web service that fails twice with 503 before succeeding
+	var tries int
+	handler := _http.HandlerFunc(
+		func(w _http.ResponseWriter, r *_http.Request) {
+			tries++
+			if tries < 3 {
+				w.WriteHeader(503)
+				return
+			}
+			fmt.Fprintf(w, `{"word":"hello","name":"Rob"}`)
+		})
+	svr := ht.NewServer(handler)
+	defer svr.Close()
+
+	http.Retry = &http.RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   time.Millisecond,
+		MaxDelay:    10 * time.Millisecond,
+		Multiplier:  2,
+		OnRetry: func(attempt int, err error, delay time.Duration) {
+			fmt.Println("retrying attempt", attempt)
+		},
+	}
+	defer func() { http.Retry = nil }()
+
+	type Greeting struct {
+		Word string `json:"word"`
+		Name string `json:"name"`
+	}
+
+	var greet Greeting
+	if err := http.Get(svr.URL, nil, &greet); err != nil {
+		fmt.Println(err)
+	}
+
+	json.Object[Greeting]{greet}.Print()
+
+	// Output:
+	// retrying attempt 1
+	// retrying attempt 2
+	// {"word":"hello","name":"Rob"}
+}
+
+func ExampleGet_options() {
+
+	// setup mock web service that echoes the auth header and a query
+	// param it received back as JSON
+	handler := _http.HandlerFunc(
+		func(w _http.ResponseWriter, r *_http.Request) {
+			out := fmt.Sprintf(`{"auth":%q,"id":%q}`,
+				r.Header.Get("Authorization"), r.URL.Query().Get("id"))
+			fmt.Fprint(w, out)
+		})
+	svr := ht.NewServer(handler)
+	defer svr.Close()
+
+	type Data struct {
+		Auth string `json:"auth"`
+		Id   string `json:"id"`
+	}
+	var data Data
+
+	err := http.Get(svr.URL, nil, &data,
+		http.WithBearer("s3cr3t"),
+		http.WithQueryParam("id", "42"),
+	)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	json.Object[Data]{data}.Print()
+
+	// Output:
+	// {"auth":"Bearer s3cr3t","id":"42"}
+}
+
+func ExamplePipeConcurrent() {
+
+	type City struct {
+		Id          int    `json:"id"`
+		Name        string `json:"name"`
+		Population  int    `json:"population"`
+		Temperature int    `json:"temperature"`
+	}
+
+	populationServiceHandler := func(w _http.ResponseWriter, r *_http.Request) {
+		fmt.Fprint(w, `{"population":8804190}`)
+	}
+	populationService := ht.NewServer(_http.HandlerFunc(populationServiceHandler))
+	defer populationService.Close()
+
+	weatherServiceHandler := func(w _http.ResponseWriter, r *_http.Request) {
+		fmt.Fprint(w, `{"temperature":46}`)
+	}
+	weatherService := ht.NewServer(_http.HandlerFunc(weatherServiceHandler))
+	defer weatherService.Close()
+
+	getPopulationReq := http.GetRecipeCtx[City](populationService.URL, nil)
+	getWeatherReq := http.GetRecipeCtx[City](weatherService.URL, nil)
+
+	merge := func(dst, src *City) {
+		if src.Population != 0 {
+			dst.Population = src.Population
+		}
+		if src.Temperature != 0 {
+			dst.Temperature = src.Temperature
+		}
+	}
+
+	city := City{Id: 1, Name: "New York"}
+
+	err := http.PipeConcurrent(&city, merge, getPopulationReq, getWeatherReq)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	json.Object[City]{city}.Print()
+
+	// Output:
+	// {"id":1,"name":"New York","population":8804190,"temperature":46}
+}
+
+// TestPipeConcurrentCancelsInFlight confirms that PipeConcurrent
+// cancels the shared context as soon as one recipe fails, so a sibling
+// recipe built with GetRecipeCtx stops waiting on its slow endpoint
+// instead of running to completion.
+func TestPipeConcurrentCancelsInFlight(t *testing.T) {
+
+	const slow = 300 * time.Millisecond
+
+	slowHandler := _http.HandlerFunc(
+		func(w _http.ResponseWriter, r *_http.Request) {
+			select {
+			case <-time.After(slow):
+				fmt.Fprint(w, `{}`)
+			case <-r.Context().Done():
+			}
+		})
+	slowService := ht.NewServer(slowHandler)
+	defer slowService.Close()
+
+	type Data struct{}
+
+	slowReq := http.GetRecipeCtx[Data](slowService.URL, nil)
+	failReq := http.CtxRecipe[Data](func(ctx context.Context, out *Data) error {
+		return fmt.Errorf("boom")
+	})
+
+	start := time.Now()
+	err := http.PipeConcurrent(&Data{}, func(dst, src *Data) {}, slowReq, failReq)
+	elapsed := time.Since(start)
+
+	if err == nil {
+		t.Fatal("expected an error from PipeConcurrent")
+	}
+	if elapsed >= slow {
+		t.Fatalf("PipeConcurrent took %s, want well under %s (in-flight request was not canceled)", elapsed, slow)
+	}
+}
+
+func ExampleAsProblem() {
+
+	// setup mock web service that fails with an RFC 7807 problem
+	// document
+	handler := _http.HandlerFunc(
+		func(w _http.ResponseWriter, r *_http.Request) {
+			w.Header().Set("Content-Type", "application/problem+json")
+			w.WriteHeader(404)
+			fmt.Fprint(w, `{"type":"https://example.com/not-found","title":"Not Found","status":404,"detail":"no such widget","widget_id":"42"}`)
+		})
+	svr := ht.NewServer(handler)
+	defer svr.Close()
+
+	type Data struct{}
+	var data Data
+
+	err := http.Get(svr.URL, nil, &data)
+
+	problem, ok := http.AsProblem(err)
+	if !ok {
+		fmt.Println("not a problem")
+		return
+	}
+
+	fmt.Println(problem.Title, problem.Status, problem.Members["widget_id"])
+
+	// Output:
+	// Not Found 404 42
+}
+
+func ExampleAsProblem_notAProblem() {
+
+	// setup mock web service that fails with a GitHub-style error body,
+	// not an RFC 7807 problem document
+	handler := _http.HandlerFunc(
+		func(w _http.ResponseWriter, r *_http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(422)
+			fmt.Fprint(w, `{"message":"Validation Failed","errors":[{"field":"name"}]}`)
+		})
+	svr := ht.NewServer(handler)
+	defer svr.Close()
+
+	type Data struct{}
+	var data Data
+
+	err := http.Get(svr.URL, nil, &data)
+
+	_, ok := http.AsProblem(err)
+	fmt.Println(ok)
+
+	// Output:
+	// false
+}
+
 func ExampleGet_status() {
 
 	// setup mock web service
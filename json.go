@@ -0,0 +1,240 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Encoder marshals a Go value into a request body along with the
+// Content-Type that describes it. Register additional Encoders to
+// extend RequestJSON and its shortcuts to other wire formats
+// (protobuf, msgpack, YAML) without changing call sites.
+type Encoder interface {
+	Encode(v any) (body []byte, contentType string, err error)
+}
+
+// Decoder unmarshals a response body, given its Content-Type, into
+// the destination pointed to by out. Register additional Decoders to
+// support response formats beyond JSON, XML, and plain text.
+type Decoder interface {
+	Decode(body []byte, contentType string, out any) error
+}
+
+// JSONEncoder is the default Encoder registered for
+// application/json.
+type JSONEncoder struct{}
+
+func (JSONEncoder) Encode(v any) ([]byte, string, error) {
+	buf, err := json.Marshal(v)
+	return buf, `application/json`, err
+}
+
+// JSONDecoder is the default Decoder registered for
+// application/json.
+type JSONDecoder struct{}
+
+func (JSONDecoder) Decode(body []byte, contentType string, out any) error {
+	return json.Unmarshal(body, out)
+}
+
+// XMLDecoder is the default Decoder registered for application/xml
+// and text/xml.
+type XMLDecoder struct{}
+
+func (XMLDecoder) Decode(body []byte, contentType string, out any) error {
+	return xml.Unmarshal(body, out)
+}
+
+// TextDecoder is the default Decoder registered for text/plain. It
+// only supports decoding into a *string destination.
+type TextDecoder struct{}
+
+func (TextDecoder) Decode(body []byte, contentType string, out any) error {
+	dst, ok := out.(*string)
+	if !ok {
+		return fmt.Errorf(`text/plain decoding requires a *string destination, got %T`, out)
+	}
+	*dst = string(body)
+	return nil
+}
+
+// Encoders maps a Content-Type to the Encoder used by RequestJSON (and
+// its PostJSON, PutJSON, PatchJSON shortcuts) to marshal request
+// bodies of that type. Register additional entries to support other
+// formats without changing call sites.
+var Encoders = map[string]Encoder{
+	`application/json`: JSONEncoder{},
+}
+
+// Decoders maps a Content-Type to the Decoder used by RequestJSON to
+// unmarshal response bodies of that type. RequestJSON inspects the
+// response's own Content-Type header (ignoring parameters such as
+// charset) and selects the matching entry, falling back to the JSON
+// decoder when the response declares a type with no registered
+// Decoder.
+var Decoders = map[string]Decoder{
+	`application/json`: JSONDecoder{},
+	`application/xml`:  XMLDecoder{},
+	`text/xml`:         XMLDecoder{},
+	`text/plain`:       TextDecoder{},
+}
+
+// RequestJSON passes the requested method and URL to the HTTP Client
+// with in (if non-nil) marshaled by the Encoder registered in
+// Encoders for application/json (or the Content-Type selected with
+// WithEncoder for this call) and sent as the request body with
+// a matching Content-Type header. The response is decoded into out by
+// looking up the Decoder registered in Decoders for the response's own
+// Content-Type header (JSON, XML, and plain text are registered by
+// default), falling back to the JSON Decoder when the response
+// Content-Type is unrecognized. RequestJSON observes the same package
+// globals TimeOut, Client, and Retry as Request, retrying transient
+// failures (network errors, 429, and 5xx responses) the same way, and
+// accepts the same Option values (WithHeader, WithBearer,
+// WithBasicAuth, WithCookie, WithTimeout, WithContext, WithQueryParam,
+// WithClient, WithEncoder) for per-call overrides. Any status code
+// other than 2xx returns an error. Also see PostJSON, PutJSON, and
+// PatchJSON.
+func RequestJSON[In, Out any](method, uri string, in *In, out *Out, opts ...Option) error {
+	o := newOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	client := Client
+	if o.Client != nil {
+		client = o.Client
+	}
+
+	timeout := time.Duration(TimeOut) * time.Second
+	if o.Timeout > 0 {
+		timeout = o.Timeout
+	}
+
+	parent := context.Background()
+	if o.Context != nil {
+		parent = o.Context
+	}
+
+	encodeAs := `application/json`
+	if o.EncodeAs != `` {
+		encodeAs = o.EncodeAs
+	}
+
+	var reqBody []byte
+	var reqContentType string
+	if in != nil {
+		enc, ok := Encoders[encodeAs]
+		if !ok {
+			return fmt.Errorf(`no encoder registered for %q`, encodeAs)
+		}
+		buf, ct, err := enc.Encode(in)
+		if err != nil {
+			return err
+		}
+		reqBody = buf
+		reqContentType = ct
+	}
+
+	buildReq := func() (*http.Request, error) {
+		var body io.Reader
+		if in != nil {
+			body = strings.NewReader(string(reqBody))
+		}
+
+		req, err := http.NewRequest(method, uri, body)
+		if err != nil {
+			return nil, err
+		}
+
+		if in != nil {
+			req.Header.Set(`Content-Type`, reqContentType)
+			req.Header.Set(`Content-Length`, strconv.Itoa(len(reqBody)))
+		}
+
+		if len(o.QueryParams) > 0 {
+			q := req.URL.Query()
+			for k, values := range o.QueryParams {
+				for _, value := range values {
+					q.Add(k, value)
+				}
+			}
+			req.URL.RawQuery = q.Encode()
+		}
+
+		for k, values := range o.Header {
+			ck := http.CanonicalHeaderKey(k)
+			for _, v := range values {
+				// Set, not Add, for Content-Type so a WithHeader override
+				// replaces the Encoder's Content-Type instead of stacking a
+				// second value on the wire.
+				if ck == `Content-Type` {
+					req.Header.Set(ck, v)
+					continue
+				}
+				req.Header.Add(k, v)
+			}
+		}
+		for _, c := range o.Cookies {
+			req.AddCookie(c)
+		}
+		if o.HasBasicAuth {
+			req.SetBasicAuth(o.BasicUser, o.BasicPass)
+		}
+
+		return req, nil
+	}
+
+	ctx, cancel := context.WithTimeout(parent, timeout)
+	defer cancel()
+
+	res, err := doWithRetry(ctx, client, method, uri, buildReq)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	buf, err := io.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+
+	contentType := res.Header.Get(`Content-Type`)
+	mediatype, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediatype = `application/json`
+	}
+
+	dec, ok := Decoders[mediatype]
+	if !ok {
+		dec = Decoders[`application/json`]
+	}
+
+	return dec.Decode(buf, mediatype, out)
+}
+
+// PostJSON sends a POST RequestJSON. Opts are the same Option values
+// accepted by Get, Post, Put, Patch, and Delete.
+func PostJSON[In, Out any](uri string, in *In, out *Out, opts ...Option) error {
+	return RequestJSON(`POST`, uri, in, out, opts...)
+}
+
+// PutJSON sends a PUT RequestJSON. Opts are the same Option values
+// accepted by Get, Post, Put, Patch, and Delete.
+func PutJSON[In, Out any](uri string, in *In, out *Out, opts ...Option) error {
+	return RequestJSON(`PUT`, uri, in, out, opts...)
+}
+
+// PatchJSON sends a PATCH RequestJSON. Opts are the same Option values
+// accepted by Get, Post, Put, Patch, and Delete.
+func PatchJSON[In, Out any](uri string, in *In, out *Out, opts ...Option) error {
+	return RequestJSON(`PATCH`, uri, in, out, opts...)
+}
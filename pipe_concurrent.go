@@ -0,0 +1,131 @@
+package http
+
+import (
+	"context"
+	"net/url"
+	"sync"
+)
+
+// CtxRecipe is a "bottled" HTTP request, like ReqRecipe, but built to
+// receive the shared context that PipeConcurrent and PipeConcurrentN
+// use to cancel in-flight requests as soon as any sibling recipe
+// fails. Build one with GetRecipeCtx, PostRecipeCtx, PutRecipeCtx,
+// PatchRecipeCtx, or DeleteRecipeCtx, which apply the given context
+// via the WithContext Option after any opts you pass, so it always
+// wins over an opt that also sets a context.
+type CtxRecipe[T any] func(ctx context.Context, out *T) error
+
+// GetRecipeCtx builds a CtxRecipe that sends a GET for use with
+// PipeConcurrent or PipeConcurrentN.
+func GetRecipeCtx[T any](uri string, in url.Values, opts ...Option) CtxRecipe[T] {
+	return CtxRecipe[T](func(ctx context.Context, out *T) error {
+		return Get(uri, in, out, append(opts, WithContext(ctx))...)
+	})
+}
+
+// PostRecipeCtx builds a CtxRecipe that sends a POST for use with
+// PipeConcurrent or PipeConcurrentN.
+func PostRecipeCtx[T any](uri string, in url.Values, opts ...Option) CtxRecipe[T] {
+	return CtxRecipe[T](func(ctx context.Context, out *T) error {
+		return Post(uri, in, out, append(opts, WithContext(ctx))...)
+	})
+}
+
+// PutRecipeCtx builds a CtxRecipe that sends a PUT for use with
+// PipeConcurrent or PipeConcurrentN.
+func PutRecipeCtx[T any](uri string, in url.Values, opts ...Option) CtxRecipe[T] {
+	return CtxRecipe[T](func(ctx context.Context, out *T) error {
+		return Put(uri, in, out, append(opts, WithContext(ctx))...)
+	})
+}
+
+// PatchRecipeCtx builds a CtxRecipe that sends a PATCH for use with
+// PipeConcurrent or PipeConcurrentN.
+func PatchRecipeCtx[T any](uri string, in url.Values, opts ...Option) CtxRecipe[T] {
+	return CtxRecipe[T](func(ctx context.Context, out *T) error {
+		return Patch(uri, in, out, append(opts, WithContext(ctx))...)
+	})
+}
+
+// DeleteRecipeCtx builds a CtxRecipe that sends a DELETE for use with
+// PipeConcurrent or PipeConcurrentN.
+func DeleteRecipeCtx[T any](uri string, opts ...Option) CtxRecipe[T] {
+	return CtxRecipe[T](func(ctx context.Context, out *T) error {
+		return Delete(uri, out, append(opts, WithContext(ctx))...)
+	})
+}
+
+// PipeConcurrent runs each recipe concurrently, each against its own
+// clone of data (a shallow copy of *data), instead of Pipe's
+// sequential, shared-struct execution. Once every recipe has finished,
+// the clones are merged back into data, in recipe order, using merge;
+// the clone of a recipe that returned an error, or was skipped, is not
+// merged.
+// It fails fast in errgroup fashion: the first recipe to return an
+// error cancels a shared context passed to every recipe, so any
+// not-yet-started recipe is skipped and any already-running recipe
+// built with GetRecipeCtx, PostRecipeCtx, PutRecipeCtx, PatchRecipeCtx,
+// or DeleteRecipeCtx has its in-flight request canceled; PipeConcurrent
+// returns that first error once every recipe has finished. See
+// PipeConcurrentN to bound how many recipes run at once.
+func PipeConcurrent[T any](data *T, merge func(dst, src *T), recipes ...CtxRecipe[T]) error {
+	return PipeConcurrentN(data, merge, 0, recipes...)
+}
+
+// PipeConcurrentN is PipeConcurrent with concurrency capped at n
+// simultaneously running recipes. A non-positive n leaves concurrency
+// unbounded.
+func PipeConcurrentN[T any](data *T, merge func(dst, src *T), n int, recipes ...CtxRecipe[T]) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var sem chan struct{}
+	if n > 0 {
+		sem = make(chan struct{}, n)
+	}
+
+	clones := make([]T, len(recipes))
+	errs := make([]error, len(recipes))
+
+	var wg sync.WaitGroup
+	var once sync.Once
+	var firstErr error
+
+	for i, recipe := range recipes {
+		clones[i] = *data
+		wg.Add(1)
+		go func(i int, recipe CtxRecipe[T]) {
+			defer wg.Done()
+
+			if sem != nil {
+				sem <- struct{}{}
+				defer func() { <-sem }()
+			}
+
+			select {
+			case <-ctx.Done():
+				errs[i] = ctx.Err()
+				return
+			default:
+			}
+
+			if err := recipe(ctx, &clones[i]); err != nil {
+				errs[i] = err
+				once.Do(func() {
+					firstErr = err
+					cancel()
+				})
+			}
+		}(i, recipe)
+	}
+
+	wg.Wait()
+
+	for i := range recipes {
+		if errs[i] == nil {
+			merge(data, &clones[i])
+		}
+	}
+
+	return firstErr
+}
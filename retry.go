@@ -0,0 +1,179 @@
+package http
+
+import (
+	"context"
+	"io"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy configures automatic retries for Request. MaxAttempts is
+// the total number of tries, including the first (a MaxAttempts of 1
+// or less disables retries). Retry n (starting at 1) waits BaseDelay
+// multiplied by Multiplier raised to n-1, so the first retry waits
+// BaseDelay itself, capped at MaxDelay, and then
+// randomized by plus or minus Jitter (a fraction between 0 and 1) to
+// avoid thundering-herd retries. OnRetry, if set, is called before
+// each wait with the attempt number (starting at 1), the error that
+// triggered the retry, and the delay about to be slept; it is useful
+// for logging and for asserting retry behavior in tests.
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+	Multiplier  float64
+	Jitter      float64
+	OnRetry     func(attempt int, err error, delay time.Duration)
+}
+
+// Retry is the package-level RetryPolicy applied by Request. It is nil
+// by default, which disables retries entirely so that existing
+// callers see no behavior change until they opt in by assigning a
+// RetryPolicy.
+var Retry *RetryPolicy
+
+// retryableStatus reports whether a response status code is worth
+// retrying: 429 Too Many Requests or any 5xx server error.
+func retryableStatus(code int) bool {
+	return code == 429 || (500 <= code && code < 600)
+}
+
+// shouldRetry reports whether Request should attempt attempt+1 given
+// the outcome of attempt, honoring the package global Retry policy.
+func shouldRetry(attempt, maxAttempts int, err error, statusCode int) bool {
+	if Retry == nil || attempt >= maxAttempts {
+		return false
+	}
+	if err != nil {
+		return true
+	}
+	return retryableStatus(statusCode)
+}
+
+// backoffDelay computes the delay before the given attempt (1-based)
+// according to policy, applying Multiplier growth, the MaxDelay cap,
+// and Jitter randomization.
+func backoffDelay(policy *RetryPolicy, attempt int) time.Duration {
+	mult := policy.Multiplier
+	if mult <= 0 {
+		mult = 1
+	}
+
+	delay := float64(policy.BaseDelay) * math.Pow(mult, float64(attempt-1))
+	if policy.MaxDelay > 0 && delay > float64(policy.MaxDelay) {
+		delay = float64(policy.MaxDelay)
+	}
+
+	if policy.Jitter > 0 {
+		spread := delay * policy.Jitter
+		delay = delay - spread + rand.Float64()*2*spread
+	}
+
+	if delay < 0 {
+		delay = 0
+	}
+
+	return time.Duration(delay)
+}
+
+// parseRetryAfter parses a Retry-After header value as either
+// delta-seconds or an HTTP-date, returning the duration to wait and
+// whether v was understood.
+func parseRetryAfter(v string) (time.Duration, bool) {
+	if v == `` {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// waitForRetry sleeps the delay computed for attempt (overridden by
+// retryAfter when it is a valid Retry-After header value), invoking
+// Retry.OnRetry beforehand, and returns early if ctx is done.
+func waitForRetry(ctx context.Context, attempt int, err error, retryAfter string) {
+	delay := backoffDelay(Retry, attempt)
+	if d, ok := parseRetryAfter(retryAfter); ok {
+		delay = d
+	}
+
+	if Retry.OnRetry != nil {
+		Retry.OnRetry(attempt, err, delay)
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+}
+
+// doWithRetry calls buildReq to construct a fresh *http.Request for
+// every attempt (so a POST/PUT/PATCH body reader, already drained by
+// the previous attempt, is rebuilt rather than reused) and sends it
+// with client under ctx, honoring the package global Retry policy for
+// transient failures (network errors, 429, and 5xx responses) the same
+// way for every caller — Request and RequestJSON alike. It returns the
+// first 2xx response, which the caller must read and close, or the
+// final error: the network error itself, or an *HTTPError built from
+// the last non-2xx response's status, header, and body.
+func doWithRetry(ctx context.Context, client *http.Client, method, uri string, buildReq func() (*http.Request, error)) (*http.Response, error) {
+	maxAttempts := 1
+	if Retry != nil && Retry.MaxAttempts > maxAttempts {
+		maxAttempts = Retry.MaxAttempts
+	}
+
+	var lastErr error
+
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		req, err := buildReq()
+		if err != nil {
+			return nil, err
+		}
+		req = req.WithContext(ctx)
+
+		res, err := client.Do(req)
+		if err != nil {
+			lastErr = err
+			if !shouldRetry(attempt, maxAttempts, err, 0) {
+				return nil, err
+			}
+			waitForRetry(ctx, attempt, err, ``)
+			continue
+		}
+
+		if !(200 <= res.StatusCode && res.StatusCode < 300) {
+			body, _ := io.ReadAll(res.Body)
+			res.Body.Close()
+			lastErr = &HTTPError{
+				StatusCode: res.StatusCode,
+				Status:     res.Status,
+				Header:     res.Header,
+				Body:       body,
+				URL:        uri,
+				Method:     method,
+			}
+			retryAfter := res.Header.Get(`Retry-After`)
+			if !shouldRetry(attempt, maxAttempts, nil, res.StatusCode) {
+				return nil, lastErr
+			}
+			waitForRetry(ctx, attempt, lastErr, retryAfter)
+			continue
+		}
+
+		return res, nil
+	}
+
+	return nil, lastErr
+}